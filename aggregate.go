@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"go.uber.org/zap"
+)
+
+// enrichmentOptions bundles the tunables for the per-function enrichment steps that run
+// once a target's functions have been listed: last-invoke lookup, tags, and metrics.
+type enrichmentOptions struct {
+	concurrency int
+	window      time.Duration
+}
+
+// allRegionsKeyword is passed to -regions to mean "every commercial AWS region". There's
+// no lightweight way to discover the regions enabled on an account without an EC2 client
+// the rest of the tool doesn't otherwise need, so the list below is kept static.
+const allRegionsKeyword = "all"
+
+// allAWSRegions is the static list of commercial AWS regions used when -regions=all.
+var allAWSRegions = []string{
+	"us-east-1", "us-east-2", "us-west-1", "us-west-2",
+	"af-south-1",
+	"ap-east-1", "ap-south-1", "ap-south-2",
+	"ap-northeast-1", "ap-northeast-2", "ap-northeast-3",
+	"ap-southeast-1", "ap-southeast-2", "ap-southeast-3", "ap-southeast-4",
+	"ca-central-1",
+	"eu-central-1", "eu-central-2",
+	"eu-west-1", "eu-west-2", "eu-west-3",
+	"eu-north-1", "eu-south-1", "eu-south-2",
+	"me-south-1", "me-central-1",
+	"sa-east-1",
+}
+
+// targetConcurrency bounds how many (profile, region) combinations are enumerated at once.
+const targetConcurrency = 8
+
+// target identifies a single (profile, region) combination to enumerate.
+type target struct {
+	profile string
+	region  string
+}
+
+// splitCSV splits a comma-separated flag value into a trimmed, non-empty list of values.
+func splitCSV(value string) []string {
+	var out []string
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// buildTargets expands the -aws-profile and -regions flag values into the full set of
+// (profile, region) combinations to enumerate, resolving the "all" regions keyword. An
+// empty -regions value means "use the profile's own default region".
+func buildTargets(profileFlag, regionsFlag string) []target {
+	profiles := splitCSV(profileFlag)
+	if len(profiles) == 0 {
+		profiles = []string{"default"}
+	}
+
+	var regions []string
+	for _, r := range splitCSV(regionsFlag) {
+		if r == allRegionsKeyword {
+			regions = append(regions, allAWSRegions...)
+			continue
+		}
+		regions = append(regions, r)
+	}
+
+	var targets []target
+	for _, profile := range profiles {
+		if len(regions) == 0 {
+			targets = append(targets, target{profile: profile})
+			continue
+		}
+		for _, region := range regions {
+			targets = append(targets, target{profile: profile, region: region})
+		}
+	}
+
+	return targets
+}
+
+// loadApplicationForTarget loads AWS config for a single (profile, region) combination and
+// builds the clients shared by every application constructor below.
+func loadApplicationForTarget(ctx context.Context, logger *zap.Logger, t target) (*application, aws.Config, error) {
+	opts := []func(*config.LoadOptions) error{
+		config.WithSharedConfigProfile(t.profile),
+	}
+	if t.region != "" {
+		opts = append(opts, config.WithRegion(t.region))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, aws.Config{}, fmt.Errorf("loading config for profile %q region %q: %w", t.profile, t.region, err)
+	}
+
+	app := &application{
+		logger:          logger,
+		lambdaClient:    lambda.NewFromConfig(cfg),
+		cwlogsClient:    cloudwatchlogs.NewFromConfig(cfg),
+		cwMetricsClient: cloudwatch.NewFromConfig(cfg),
+	}
+
+	return app, cfg, nil
+}
+
+// newApplicationForTarget loads AWS config for a single (profile, region) combination and
+// builds the clients needed to enumerate it, along with the caller's AWS account ID.
+func newApplicationForTarget(ctx context.Context, logger *zap.Logger, t target) (*application, string, error) {
+	app, cfg, err := loadApplicationForTarget(ctx, logger, t)
+	if err != nil {
+		return nil, "", err
+	}
+
+	identity, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, "", fmt.Errorf("getting caller identity for profile %q region %q: %w", t.profile, t.region, err)
+	}
+
+	return app, aws.ToString(identity.Account), nil
+}
+
+// newApplicationForLogs loads AWS config for a single (profile, region) combination and
+// builds the clients needed to tail CloudWatch Logs. Unlike newApplicationForTarget, it
+// skips the GetCallerIdentity call: the "logs" subcommand has no use for the account ID,
+// so it shouldn't pay for the extra round trip or require sts:GetCallerIdentity.
+func newApplicationForLogs(ctx context.Context, logger *zap.Logger, t target) (*application, error) {
+	app, _, err := loadApplicationForTarget(ctx, logger, t)
+	return app, err
+}
+
+// getAllLambdaFunctionsAcrossTargets enumerates every (profile, region) combination in
+// targets in bounded parallel, merging the resulting function lists into one, each
+// stamped with its AWS Account ID, Profile, and Region. The per-function last-invoke
+// lookup runs underneath each target with its own worker pool, as usual.
+func getAllLambdaFunctionsAcrossTargets(ctx context.Context, logger *zap.Logger, targets []target, opts enrichmentOptions) ([]lambdaFunctionDetails, error) {
+	var (
+		mu     sync.Mutex
+		merged []lambdaFunctionDetails
+		errs   []error
+	)
+
+	runBounded(len(targets), targetConcurrency, func(index int) {
+		t := targets[index]
+
+		app, accountID, err := newApplicationForTarget(ctx, logger, t)
+		if err != nil {
+			logger.Error("error when setting up target",
+				zap.String("profile", t.profile),
+				zap.String("region", t.region),
+				zap.Error(err),
+			)
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+			return
+		}
+
+		list, err := app.getAllLambdaFunctionsDetailsList()
+		if err != nil {
+			logger.Error("error when listing lambda function details",
+				zap.String("profile", t.profile),
+				zap.String("region", t.region),
+				zap.Error(err),
+			)
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+			return
+		}
+
+		for i := range list {
+			list[i].AccountID = accountID
+			list[i].Profile = t.profile
+			list[i].Region = t.region
+			if list[i].Region == "" {
+				// No explicit region: take the profile's default region from the function
+				// ARN (arn:aws:lambda:<region>:<account>:function:<name>).
+				if parts := strings.Split(list[i].ARN, ":"); len(parts) > 3 {
+					list[i].Region = parts[3]
+				}
+			}
+		}
+
+		app.getAllLambdaFunctionsLastInvokeTime(ctx, list, opts.concurrency)
+		app.getAllLambdaFunctionsTags(ctx, list, opts.concurrency)
+
+		if err := app.getAllLambdaFunctionsMetrics(ctx, list, opts.window); err != nil {
+			logger.Error("error when getting lambda function metrics",
+				zap.String("profile", t.profile),
+				zap.String("region", t.region),
+				zap.Error(err),
+			)
+		}
+
+		mu.Lock()
+		merged = append(merged, list...)
+		mu.Unlock()
+	})
+
+	if len(merged) == 0 && len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	return merged, nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitCSV(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{"empty", "", nil},
+		{"single", "default", []string{"default"}},
+		{"multiple", "a,b,c", []string{"a", "b", "c"}},
+		{"whitespace trimmed", " a , b ,c ", []string{"a", "b", "c"}},
+		{"blank entries dropped", "a,,b,", []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitCSV(tt.value)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("splitCSV(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildTargetsDefaultsToSingleDefaultProfileAndRegion(t *testing.T) {
+	got := buildTargets("", "")
+	want := []target{{profile: "default"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("buildTargets(\"\", \"\") = %+v, want %+v", got, want)
+	}
+}
+
+func TestBuildTargetsSingleProfileMultipleRegions(t *testing.T) {
+	got := buildTargets("default", "us-east-1,eu-west-1")
+	want := []target{
+		{profile: "default", region: "us-east-1"},
+		{profile: "default", region: "eu-west-1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestBuildTargetsMultipleProfilesMultipleRegions(t *testing.T) {
+	got := buildTargets("dev,prod", "us-east-1,eu-west-1")
+	want := []target{
+		{profile: "dev", region: "us-east-1"},
+		{profile: "dev", region: "eu-west-1"},
+		{profile: "prod", region: "us-east-1"},
+		{profile: "prod", region: "eu-west-1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestBuildTargetsAllRegionsKeywordExpandsToStaticList(t *testing.T) {
+	got := buildTargets("default", "all")
+	if len(got) != len(allAWSRegions) {
+		t.Fatalf("got %d targets, want %d (one per region in allAWSRegions)", len(got), len(allAWSRegions))
+	}
+	for i, region := range allAWSRegions {
+		if got[i] != (target{profile: "default", region: region}) {
+			t.Fatalf("target %d = %+v, want profile=default region=%s", i, got[i], region)
+		}
+	}
+}
+
+func TestBuildTargetsMultipleProfilesNoRegion(t *testing.T) {
+	got := buildTargets("dev,prod", "")
+	want := []target{{profile: "dev"}, {profile: "prod"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
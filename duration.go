@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dayDuration is a flag.Value that parses the same syntax as time.ParseDuration, but also
+// accepts a bare day count with a "d" suffix, e.g. "90d" — the natural unit for
+// "-stale"/"-window" flags that describe how far back to look. The "d" form cannot be
+// combined with other units ("1d12h" is rejected).
+type dayDuration time.Duration
+
+func (d *dayDuration) String() string {
+	return time.Duration(*d).String()
+}
+
+func (d *dayDuration) Set(value string) error {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil {
+			return fmt.Errorf("invalid day count %q: %w", days, err)
+		}
+		*d = dayDuration(time.Duration(n * float64(24*time.Hour)))
+		return nil
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = dayDuration(parsed)
+	return nil
+}
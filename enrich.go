@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"go.uber.org/zap"
+)
+
+// lambdaAPITPSLimit is a conservative default for the Lambda control-plane API
+// throttling ceiling (requests per second) per account, shared across all workers in a
+// pool looking up per-function details such as tags.
+const lambdaAPITPSLimit = 5
+
+func formatInt32(v *int32) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatInt(int64(*v), 10)
+}
+
+func joinArchitectures(architectures []types.Architecture) string {
+	values := make([]string, len(architectures))
+	for i, a := range architectures {
+		values[i] = string(a)
+	}
+	return strings.Join(values, ",")
+}
+
+func joinLayers(layers []types.Layer) string {
+	values := make([]string, len(layers))
+	for i, l := range layers {
+		values[i] = aws.ToString(l.Arn)
+	}
+	return strings.Join(values, ",")
+}
+
+func deadLetterTargetArn(cfg *types.DeadLetterConfig) string {
+	if cfg == nil {
+		return ""
+	}
+	return aws.ToString(cfg.TargetArn)
+}
+
+func tracingMode(cfg *types.TracingConfigResponse) string {
+	if cfg == nil {
+		return ""
+	}
+	return string(cfg.Mode)
+}
+
+func vpcID(cfg *types.VpcConfigResponse) string {
+	if cfg == nil {
+		return ""
+	}
+	return aws.ToString(cfg.VpcId)
+}
+
+// getLambdaFunctionTags fetches the tags for a single function and stores them in
+// outputList[index] as a single JSON-encoded column, matching how the rest of the tool
+// flattens structured AWS data into one string field per column.
+func (app *application) getLambdaFunctionTags(ctx context.Context, index int, outputList []lambdaFunctionDetails) {
+	functionName := outputList[index].Name
+
+	out, err := app.lambdaClient.ListTags(ctx, &lambda.ListTagsInput{
+		Resource: aws.String(outputList[index].ARN),
+	})
+	if err != nil {
+		app.logger.Debug("error when listing tags",
+			zap.Error(err),
+			zap.String("function_name", functionName),
+		)
+		return
+	}
+
+	if len(out.Tags) == 0 {
+		return
+	}
+
+	encoded, err := json.Marshal(out.Tags)
+	if err != nil {
+		app.logger.Debug("error when encoding tags",
+			zap.Error(err),
+			zap.String("function_name", functionName),
+		)
+		return
+	}
+
+	outputList[index].Tags = string(encoded)
+}
+
+// getAllLambdaFunctionsTags fetches tags for every function in outputList, bounded to
+// concurrency concurrent workers sharing a token-bucket rate limiter, the same pattern
+// used for the last-invoke-time lookup.
+func (app *application) getAllLambdaFunctionsTags(ctx context.Context, outputList []lambdaFunctionDetails, concurrency int) {
+	pool := newWorkerPool(ctx, concurrency, lambdaAPITPSLimit)
+
+	pool.run(ctx, len(outputList), func(ctx context.Context, index int) {
+		app.getLambdaFunctionTags(ctx, index, outputList)
+	})
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+func TestFormatInt32(t *testing.T) {
+	if got := formatInt32(nil); got != "" {
+		t.Fatalf("formatInt32(nil) = %q, want empty string", got)
+	}
+	v := int32(512)
+	if got := formatInt32(&v); got != "512" {
+		t.Fatalf("formatInt32(&512) = %q, want %q", got, "512")
+	}
+}
+
+func TestJoinArchitectures(t *testing.T) {
+	if got := joinArchitectures(nil); got != "" {
+		t.Fatalf("joinArchitectures(nil) = %q, want empty string", got)
+	}
+	got := joinArchitectures([]types.Architecture{types.ArchitectureX8664, types.ArchitectureArm64})
+	if want := "x86_64,arm64"; got != want {
+		t.Fatalf("joinArchitectures(...) = %q, want %q", got, want)
+	}
+}
+
+func TestJoinLayers(t *testing.T) {
+	if got := joinLayers(nil); got != "" {
+		t.Fatalf("joinLayers(nil) = %q, want empty string", got)
+	}
+	layers := []types.Layer{
+		{Arn: aws.String("arn:aws:lambda:us-east-1:123:layer:one:1")},
+		{Arn: aws.String("arn:aws:lambda:us-east-1:123:layer:two:3")},
+	}
+	got := joinLayers(layers)
+	want := "arn:aws:lambda:us-east-1:123:layer:one:1,arn:aws:lambda:us-east-1:123:layer:two:3"
+	if got != want {
+		t.Fatalf("joinLayers(...) = %q, want %q", got, want)
+	}
+}
+
+func TestDeadLetterTargetArn(t *testing.T) {
+	if got := deadLetterTargetArn(nil); got != "" {
+		t.Fatalf("deadLetterTargetArn(nil) = %q, want empty string", got)
+	}
+	cfg := &types.DeadLetterConfig{TargetArn: aws.String("arn:aws:sqs:us-east-1:123:my-dlq")}
+	if got := deadLetterTargetArn(cfg); got != "arn:aws:sqs:us-east-1:123:my-dlq" {
+		t.Fatalf("deadLetterTargetArn(...) = %q, want %q", got, "arn:aws:sqs:us-east-1:123:my-dlq")
+	}
+}
+
+func TestTracingMode(t *testing.T) {
+	if got := tracingMode(nil); got != "" {
+		t.Fatalf("tracingMode(nil) = %q, want empty string", got)
+	}
+	cfg := &types.TracingConfigResponse{Mode: types.TracingModeActive}
+	if got := tracingMode(cfg); got != "Active" {
+		t.Fatalf("tracingMode(...) = %q, want %q", got, "Active")
+	}
+}
+
+func TestVpcID(t *testing.T) {
+	if got := vpcID(nil); got != "" {
+		t.Fatalf("vpcID(nil) = %q, want empty string", got)
+	}
+	cfg := &types.VpcConfigResponse{VpcId: aws.String("vpc-0123456789abcdef0")}
+	if got := vpcID(cfg); got != "vpc-0123456789abcdef0" {
+		t.Fatalf("vpcID(...) = %q, want %q", got, "vpc-0123456789abcdef0")
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fieldAccessors maps the identifiers usable in a -filter expression to the
+// lambdaFunctionDetails field they read.
+var fieldAccessors = map[string]func(lambdaFunctionDetails) string{
+	"name":           func(l lambdaFunctionDetails) string { return l.Name },
+	"arn":            func(l lambdaFunctionDetails) string { return l.ARN },
+	"description":    func(l lambdaFunctionDetails) string { return l.Description },
+	"lastModified":   func(l lambdaFunctionDetails) string { return l.LastModified },
+	"iamRole":        func(l lambdaFunctionDetails) string { return l.IAMRole },
+	"runtime":        func(l lambdaFunctionDetails) string { return l.Runtime },
+	"lastInvoked":    func(l lambdaFunctionDetails) string { return l.LastInvoked },
+	"accountId":      func(l lambdaFunctionDetails) string { return l.AccountID },
+	"profile":        func(l lambdaFunctionDetails) string { return l.Profile },
+	"region":         func(l lambdaFunctionDetails) string { return l.Region },
+	"memory":         func(l lambdaFunctionDetails) string { return l.MemorySize },
+	"timeout":        func(l lambdaFunctionDetails) string { return l.Timeout },
+	"codeSize":       func(l lambdaFunctionDetails) string { return l.CodeSize },
+	"packageType":    func(l lambdaFunctionDetails) string { return l.PackageType },
+	"architectures":  func(l lambdaFunctionDetails) string { return l.Architectures },
+	"handler":        func(l lambdaFunctionDetails) string { return l.Handler },
+	"layers":         func(l lambdaFunctionDetails) string { return l.Layers },
+	"deadLetterArn":  func(l lambdaFunctionDetails) string { return l.DeadLetterArn },
+	"tracingMode":    func(l lambdaFunctionDetails) string { return l.TracingMode },
+	"vpcId":          func(l lambdaFunctionDetails) string { return l.VpcID },
+	"invocations":    func(l lambdaFunctionDetails) string { return l.InvocationsSum },
+	"errors":         func(l lambdaFunctionDetails) string { return l.ErrorsSum },
+	"throttles":      func(l lambdaFunctionDetails) string { return l.ThrottlesSum },
+	"durationP50Ms":  func(l lambdaFunctionDetails) string { return l.DurationP50Ms },
+	"durationP95Ms":  func(l lambdaFunctionDetails) string { return l.DurationP95Ms },
+}
+
+// fieldValue resolves an identifier to its string value for l, supporting the
+// "tag:<Key>" accessor over the JSON-encoded Tags column.
+func fieldValue(l lambdaFunctionDetails, accessor string) (string, error) {
+	if key, ok := strings.CutPrefix(accessor, "tag:"); ok {
+		if l.Tags == "" {
+			return "", nil
+		}
+		var tags map[string]string
+		if err := json.Unmarshal([]byte(l.Tags), &tags); err != nil {
+			return "", fmt.Errorf("decoding tags for %q: %w", l.Name, err)
+		}
+		return tags[key], nil
+	}
+
+	get, ok := fieldAccessors[accessor]
+	if !ok {
+		return "", fmt.Errorf("unknown filter field %q", accessor)
+	}
+	return get(l), nil
+}
+
+// filterExpr is a node in the AST produced by parseFilter: either a boolean combinator
+// (and/or/not) or a leaf comparison.
+type filterExpr interface {
+	eval(l lambdaFunctionDetails) (bool, error)
+}
+
+type andExpr struct{ left, right filterExpr }
+
+func (e andExpr) eval(l lambdaFunctionDetails) (bool, error) {
+	lv, err := e.left.eval(l)
+	if err != nil || !lv {
+		return false, err
+	}
+	return e.right.eval(l)
+}
+
+type orExpr struct{ left, right filterExpr }
+
+func (e orExpr) eval(l lambdaFunctionDetails) (bool, error) {
+	lv, err := e.left.eval(l)
+	if err != nil {
+		return false, err
+	}
+	if lv {
+		return true, nil
+	}
+	return e.right.eval(l)
+}
+
+type notExpr struct{ inner filterExpr }
+
+func (e notExpr) eval(l lambdaFunctionDetails) (bool, error) {
+	v, err := e.inner.eval(l)
+	return !v, err
+}
+
+// comparisonExpr is a leaf node comparing a field's value against a literal.
+type comparisonExpr struct {
+	accessor string
+	op       string
+	literal  string
+}
+
+func (e comparisonExpr) eval(l lambdaFunctionDetails) (bool, error) {
+	value, err := fieldValue(l, e.accessor)
+	if err != nil {
+		return false, err
+	}
+
+	switch e.op {
+	case "==":
+		return value == e.literal, nil
+	case "!=":
+		return value != e.literal, nil
+	case "=~":
+		re, err := regexp.Compile(e.literal)
+		if err != nil {
+			return false, fmt.Errorf("invalid regexp %q: %w", e.literal, err)
+		}
+		return re.MatchString(value), nil
+	case "<":
+		if isUnorderableSentinel(value) {
+			return false, nil
+		}
+		return compareOrdered(value, e.literal)
+	case ">":
+		if isUnorderableSentinel(value) {
+			return false, nil
+		}
+		return compareOrdered(e.literal, value)
+	default:
+		return false, fmt.Errorf("unsupported operator %q", e.op)
+	}
+}
+
+// isUnorderableSentinel reports whether v is one of the sentinel strings
+// getLambdaFunctionLastInvokeTime stamps in place of a real timestamp when the last-invoke
+// lookup failed or found nothing (see main.go). Neither sentinel has a meaningful position
+// in timestamp or integer ordering, so a "<"/">" comparison against one simply doesn't
+// match rather than erroring out — -stale's whole point is to also catch functions in
+// this state.
+func isUnorderableSentinel(v string) bool {
+	return v == "Not Found" || v == "Error"
+}
+
+// timestampLayouts are the layouts compareOrdered tries when deciding whether two values
+// are timestamps rather than plain numbers.
+var timestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02",
+}
+
+func parseTimestamp(value string) (time.Time, bool) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// compareOrdered reports whether a < b, treating both as ISO timestamps if they parse as
+// such, otherwise as integers, otherwise as floats (e.g. durationP50Ms/durationP95Ms,
+// which are formatted with strconv.FormatFloat).
+func compareOrdered(a, b string) (bool, error) {
+	if at, ok := parseTimestamp(a); ok {
+		if bt, ok := parseTimestamp(b); ok {
+			return at.Before(bt), nil
+		}
+	}
+
+	an, aErr := strconv.ParseInt(a, 10, 64)
+	bn, bErr := strconv.ParseInt(b, 10, 64)
+	if aErr == nil && bErr == nil {
+		return an < bn, nil
+	}
+
+	af, aErr := strconv.ParseFloat(a, 64)
+	bf, bErr := strconv.ParseFloat(b, 64)
+	if aErr == nil && bErr == nil {
+		return af < bf, nil
+	}
+
+	return false, fmt.Errorf("cannot order %q and %q: neither both timestamps, both integers, nor both floats", a, b)
+}
+
+// filterFunctions evaluates expr against every element of list, returning only the
+// functions for which it evaluates to true.
+func filterFunctions(list []lambdaFunctionDetails, expr filterExpr) ([]lambdaFunctionDetails, error) {
+	if expr == nil {
+		return list, nil
+	}
+
+	var filtered []lambdaFunctionDetails
+	for _, l := range list {
+		ok, err := expr.eval(l)
+		if err != nil {
+			return nil, fmt.Errorf("evaluating filter for %q: %w", l.Name, err)
+		}
+		if ok {
+			filtered = append(filtered, l)
+		}
+	}
+
+	return filtered, nil
+}
+
+// buildFilterExpr combines the -filter expression and the -stale shortcut, ANDing them
+// together when both are set. It returns a nil expr (meaning "no filtering") when
+// neither flag is set.
+func buildFilterExpr(filter string, stale time.Duration) (filterExpr, error) {
+	var expr filterExpr
+
+	if filter != "" {
+		parsed, err := parseFilter(filter)
+		if err != nil {
+			return nil, fmt.Errorf("parsing -filter: %w", err)
+		}
+		expr = parsed
+	}
+
+	if stale > 0 {
+		staleExpr := staleFilterExpr(time.Now(), stale)
+		if expr == nil {
+			expr = staleExpr
+		} else {
+			expr = andExpr{left: expr, right: staleExpr}
+		}
+	}
+
+	return expr, nil
+}
+
+// staleFilterExpr builds the expression -stale <duration> is shorthand for: functions
+// whose last invoke time is older than now-duration, that have never been invoked, or
+// whose last-invoke lookup failed — all three are "worth a look" in the same way.
+func staleFilterExpr(now time.Time, staleAfter time.Duration) filterExpr {
+	cutoff := now.Add(-staleAfter).Format(time.RFC3339)
+
+	return orExpr{
+		left: comparisonExpr{accessor: "lastInvoked", op: "<", literal: cutoff},
+		right: orExpr{
+			left:  comparisonExpr{accessor: "lastInvoked", op: "==", literal: "Not Found"},
+			right: comparisonExpr{accessor: "lastInvoked", op: "==", literal: "Error"},
+		},
+	}
+}
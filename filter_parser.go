@@ -0,0 +1,260 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind enumerates the lexical categories produced by lexFilter.
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+// lexFilter tokenizes a -filter expression such as:
+//
+//	runtime=~"python3\..*" && lastInvoked<"2024-01-01" && memory>512 && tag:Env=="prod"
+func lexFilter(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen})
+			i++
+
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen})
+			i++
+
+		case r == '"':
+			value, consumed, err := lexString(runes[i:])
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokString, value: value})
+			i += consumed
+
+		case strings.HasPrefix(string(runes[i:]), "&&"):
+			tokens = append(tokens, token{kind: tokAnd})
+			i += 2
+
+		case strings.HasPrefix(string(runes[i:]), "||"):
+			tokens = append(tokens, token{kind: tokOr})
+			i += 2
+
+		case strings.HasPrefix(string(runes[i:]), "=="):
+			tokens = append(tokens, token{kind: tokOp, value: "=="})
+			i += 2
+
+		case strings.HasPrefix(string(runes[i:]), "!="):
+			tokens = append(tokens, token{kind: tokOp, value: "!="})
+			i += 2
+
+		case strings.HasPrefix(string(runes[i:]), "=~"):
+			tokens = append(tokens, token{kind: tokOp, value: "=~"})
+			i += 2
+
+		case r == '<' || r == '>':
+			tokens = append(tokens, token{kind: tokOp, value: string(r)})
+			i++
+
+		case r == '!':
+			tokens = append(tokens, token{kind: tokNot})
+			i++
+
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokNumber, value: string(runes[i:j])})
+			i = j
+
+		case isIdentRune(r):
+			j := i
+			for j < len(runes) && isIdentRune(runes[j]) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokIdent, value: string(runes[i:j])})
+			i = j
+
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+		}
+	}
+
+	return tokens, nil
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == ':' || r == '.'
+}
+
+// lexString reads a double-quoted, backslash-escaped string starting at runes[0] (which
+// must be '"'), returning its unescaped contents and the number of runes consumed.
+func lexString(runes []rune) (string, int, error) {
+	var sb strings.Builder
+
+	for i := 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 >= len(runes) {
+				return "", 0, fmt.Errorf("unterminated escape in string literal")
+			}
+			sb.WriteRune(runes[i+1])
+			i++
+		case '"':
+			return sb.String(), i + 1, nil
+		default:
+			sb.WriteRune(runes[i])
+		}
+	}
+
+	return "", 0, fmt.Errorf("unterminated string literal")
+}
+
+// filterParser is a recursive-descent parser over the token stream produced by lexFilter.
+type filterParser struct {
+	tokens []token
+	pos    int
+}
+
+// parseFilter parses a -filter expression into a filterExpr AST.
+func parseFilter(input string) (filterExpr, error) {
+	tokens, err := lexFilter(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokEOF {
+		return nil, fmt.Errorf("unexpected trailing input at token %d", p.pos)
+	}
+
+	return expr, nil
+}
+
+func (p *filterParser) peek() token {
+	if p.pos >= len(p.tokens) {
+		return token{kind: tokEOF}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() token {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *filterParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filterExpr, error) {
+	if p.peek().kind == tokNot {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{inner: inner}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *filterParser) parsePrimary() (filterExpr, error) {
+	if p.peek().kind == tokLParen {
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokRParen {
+			return nil, fmt.Errorf("expected ')' at token %d", p.pos)
+		}
+		p.next()
+		return expr, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterExpr, error) {
+	accessorTok := p.next()
+	if accessorTok.kind != tokIdent {
+		return nil, fmt.Errorf("expected a field name at token %d", p.pos-1)
+	}
+
+	opTok := p.next()
+	if opTok.kind != tokOp {
+		return nil, fmt.Errorf("expected an operator (==, !=, =~, <, >) after %q", accessorTok.value)
+	}
+
+	literalTok := p.next()
+	if literalTok.kind != tokString && literalTok.kind != tokNumber {
+		return nil, fmt.Errorf("expected a string or number literal after %q %q", accessorTok.value, opTok.value)
+	}
+
+	return comparisonExpr{accessor: accessorTok.value, op: opTok.value, literal: literalTok.value}, nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAndEvalFilter(t *testing.T) {
+	fn := lambdaFunctionDetails{
+		Name:          "my-function",
+		Runtime:       "python3.12",
+		MemorySize:    "1024",
+		LastInvoked:   "2024-06-01T00:00:00Z",
+		Tags:          `{"Env":"prod"}`,
+		DurationP50Ms: "12.30",
+		DurationP95Ms: "45.60",
+	}
+
+	tests := []struct {
+		name   string
+		filter string
+		want   bool
+	}{
+		{"equality match", `runtime=="python3.12"`, true},
+		{"equality mismatch", `runtime=="nodejs20.x"`, false},
+		{"inequality", `runtime!="nodejs20.x"`, true},
+		{"regexp match", `runtime=~"python3\\..*"`, true},
+		{"regexp mismatch", `runtime=~"^nodejs"`, false},
+		{"numeric greater than", `memory>512`, true},
+		{"numeric less than", `memory<512`, false},
+		{"tag accessor match", `tag:Env=="prod"`, true},
+		{"tag accessor mismatch", `tag:Env=="dev"`, false},
+		{"and both true", `runtime=="python3.12" && memory>512`, true},
+		{"and one false", `runtime=="python3.12" && memory<512`, false},
+		{"or one true", `runtime=="nodejs20.x" || memory>512`, true},
+		{"not", `!(runtime=="nodejs20.x")`, true},
+		{"parens", `(runtime=="python3.12" || runtime=="nodejs20.x") && memory>512`, true},
+		{"timestamp ordering", `lastInvoked<"2024-12-31T00:00:00Z"`, true},
+		{"float field greater than", `durationP95Ms>20`, true},
+		{"float field less than", `durationP50Ms<20`, true},
+		{"float field non-match", `durationP50Ms>20`, false},
+		{"float literal comparison", `durationP95Ms>45.5`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := parseFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("parseFilter(%q): %v", tt.filter, err)
+			}
+			got, err := expr.eval(fn)
+			if err != nil {
+				t.Fatalf("eval(%q): %v", tt.filter, err)
+			}
+			if got != tt.want {
+				t.Fatalf("eval(%q) = %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	tests := []string{
+		`runtime==`,
+		`runtime="python3.12"`,
+		`(runtime=="x"`,
+		`runtime=="x" extra`,
+	}
+
+	for _, filter := range tests {
+		if _, err := parseFilter(filter); err == nil {
+			t.Fatalf("parseFilter(%q): expected an error, got nil", filter)
+		}
+	}
+}
+
+func TestEvalFilterUnknownField(t *testing.T) {
+	expr, err := parseFilter(`unknownField=="x"`)
+	if err != nil {
+		t.Fatalf("parseFilter: %v", err)
+	}
+	if _, err := expr.eval(lambdaFunctionDetails{}); err == nil {
+		t.Fatal("eval: expected an error for an unknown field, got nil")
+	}
+}
+
+// TestStaleFilterSentinelValues is a regression test: staleFilterExpr ORs a "<" timestamp
+// comparison with an "==" sentinel comparison, and ordering the "Not Found"/"Error"
+// sentinels that getLambdaFunctionLastInvokeTime stamps in place of a real timestamp must
+// not error out filterFunctions (and so abort the whole run via logger.Fatal in runList).
+func TestStaleFilterSentinelValues(t *testing.T) {
+	list := []lambdaFunctionDetails{
+		{Name: "never-invoked", LastInvoked: "Not Found"},
+		{Name: "lookup-failed", LastInvoked: "Error"},
+		{Name: "invoked-long-ago", LastInvoked: "2000-01-01T00:00:00Z"},
+		{Name: "invoked-recently", LastInvoked: time.Now().Format(time.RFC3339)},
+	}
+
+	expr, err := buildFilterExpr("", 90*24*time.Hour)
+	if err != nil {
+		t.Fatalf("buildFilterExpr: %v", err)
+	}
+
+	got, err := filterFunctions(list, expr)
+	if err != nil {
+		t.Fatalf("filterFunctions: %v", err)
+	}
+
+	var gotNames []string
+	for _, l := range got {
+		gotNames = append(gotNames, l.Name)
+	}
+	want := []string{"never-invoked", "lookup-failed", "invoked-long-ago"}
+	if len(gotNames) != len(want) {
+		t.Fatalf("got %v, want %v", gotNames, want)
+	}
+	for i, name := range want {
+		if gotNames[i] != name {
+			t.Fatalf("got %v, want %v", gotNames, want)
+		}
+	}
+}
+
+func TestCompareOrdered(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"2024-01-01T00:00:00Z", "2024-06-01T00:00:00Z", true},
+		{"2024-06-01T00:00:00Z", "2024-01-01T00:00:00Z", false},
+		{"10", "20", true},
+		{"20", "10", false},
+		{"12.30", "45.60", true},
+		{"45.60", "12.30", false},
+	}
+
+	for _, tt := range tests {
+		got, err := compareOrdered(tt.a, tt.b)
+		if err != nil {
+			t.Fatalf("compareOrdered(%q, %q): %v", tt.a, tt.b, err)
+		}
+		if got != tt.want {
+			t.Fatalf("compareOrdered(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	if _, err := compareOrdered("Not Found", "2024-01-01T00:00:00Z"); err == nil {
+		t.Fatal("expected an error ordering an unparseable value, got nil")
+	}
+}
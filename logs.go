@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"go.uber.org/zap"
+)
+
+// logsPollInterval is how often --follow polls for new log events.
+const logsPollInterval = 2 * time.Second
+
+// logEvent is the JSON shape of a single rendered CloudWatch Logs event, used when --json
+// is passed.
+type logEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	LogStream string    `json:"logStream"`
+	Message   string    `json:"message"`
+}
+
+// runLogs implements the "logs" subcommand: it locates the /aws/lambda/<name> log group
+// and streams matching events across all of its log streams, optionally following new
+// ones as they arrive.
+func runLogs(args []string) {
+	fs := flag.NewFlagSet("logs", flag.ExitOnError)
+	showDebugLog := fs.Bool("show-debug-log", false, "Show debug log")
+	awsProfileName := fs.String("aws-profile", "default", "AWS Profile Name")
+	region := fs.String("region", "", "AWS Region (defaults to the profile's own region)")
+	since := fs.Duration("since", 15*time.Minute, "How far back to fetch log events from")
+	filterPattern := fs.String("filter", "", "CloudWatch Logs filter pattern")
+	follow := fs.Bool("follow", false, "Keep polling for new log events")
+	jsonOutput := fs.Bool("json", false, "Render events as JSON instead of tab-separated text")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "usage: aws-lambda-lister logs <function-name> [flags]")
+		os.Exit(1)
+	}
+	functionName := fs.Arg(0)
+
+	logger := createLogger(*showDebugLog)
+	defer logger.Sync()
+
+	ctx := context.Background()
+	app, err := newApplicationForLogs(ctx, logger, target{profile: *awsProfileName, region: *region})
+	if err != nil {
+		logger.Fatal("error when setting up AWS clients",
+			zap.Error(err),
+		)
+	}
+
+	logGroupName := fmt.Sprintf("%s%s", lambdaLogGroupPrefix, functionName)
+	startTime := time.Now().Add(-*since)
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	seenEventIDs := make(map[string]struct{})
+
+	for {
+		lastEventTime, err := streamLogEvents(ctx, app, w, logGroupName, startTime, *filterPattern, *jsonOutput, seenEventIDs)
+		if err != nil {
+			logger.Fatal("error when filtering log events",
+				zap.String("log group name", logGroupName),
+				zap.Error(err),
+			)
+		}
+		w.Flush()
+
+		if !*follow {
+			return
+		}
+
+		if lastEventTime.After(startTime) {
+			startTime = lastEventTime
+		}
+
+		time.Sleep(logsPollInterval)
+	}
+}
+
+// streamLogEvents fetches every log event for logGroupName since startTime matching
+// filterPattern, across all of the group's log streams, paging through FilterLogEvents
+// via NextToken. It de-duplicates by event ID, since --follow re-requests a time range
+// that overlaps the previous poll, and returns the timestamp of the most recent event
+// seen so the caller can use it as the next poll's cursor.
+func streamLogEvents(ctx context.Context, app *application, w *bufio.Writer, logGroupName string, startTime time.Time, filterPattern string, jsonOutput bool, seenEventIDs map[string]struct{}) (time.Time, error) {
+	lastEventTime := startTime
+
+	input := &cloudwatchlogs.FilterLogEventsInput{
+		LogGroupName: aws.String(logGroupName),
+		StartTime:    aws.Int64(startTime.UnixMilli()),
+	}
+	if filterPattern != "" {
+		input.FilterPattern = aws.String(filterPattern)
+	}
+
+	for {
+		var out *cloudwatchlogs.FilterLogEventsOutput
+		err := withRetry(ctx, defaultRetryConfig, func() error {
+			var callErr error
+			out, callErr = app.cwlogsClient.FilterLogEvents(ctx, input)
+			return callErr
+		})
+		if err != nil {
+			return lastEventTime, err
+		}
+
+		for _, event := range out.Events {
+			id := aws.ToString(event.EventId)
+			if _, ok := seenEventIDs[id]; ok {
+				continue
+			}
+			seenEventIDs[id] = struct{}{}
+
+			eventTime := time.UnixMilli(aws.ToInt64(event.Timestamp))
+			if eventTime.After(lastEventTime) {
+				lastEventTime = eventTime
+			}
+
+			writeLogEvent(w, eventTime, aws.ToString(event.LogStreamName), aws.ToString(event.Message), jsonOutput)
+		}
+
+		if out.NextToken == nil {
+			break
+		}
+		input.NextToken = out.NextToken
+	}
+
+	return lastEventTime, nil
+}
+
+func writeLogEvent(w *bufio.Writer, timestamp time.Time, stream, message string, jsonOutput bool) {
+	if jsonOutput {
+		encoded, err := json.Marshal(logEvent{Timestamp: timestamp, LogStream: stream, Message: message})
+		if err != nil {
+			return
+		}
+		fmt.Fprintln(w, string(encoded))
+		return
+	}
+
+	fmt.Fprintf(w, "%s\t%s\t%s\n", timestamp.Format("2006-01-02T15:04:05-07:00"), stream, message)
+}
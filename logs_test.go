@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteLogEventText(t *testing.T) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+
+	ts := time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC)
+	writeLogEvent(w, ts, "2024/06/01/[$LATEST]abc123", "START RequestId: abc123", false)
+	w.Flush()
+
+	got := buf.String()
+	if !strings.Contains(got, "2024/06/01/[$LATEST]abc123") {
+		t.Fatalf("output %q missing log stream name", got)
+	}
+	if !strings.Contains(got, "START RequestId: abc123") {
+		t.Fatalf("output %q missing message", got)
+	}
+	if !strings.HasPrefix(got, ts.Format("2006-01-02T15:04:05-07:00")) {
+		t.Fatalf("output %q does not start with the formatted timestamp", got)
+	}
+}
+
+func TestWriteLogEventJSON(t *testing.T) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+
+	ts := time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC)
+	writeLogEvent(w, ts, "2024/06/01/[$LATEST]abc123", "START RequestId: abc123", true)
+	w.Flush()
+
+	var got logEvent
+	if err := json.Unmarshal(bytes.TrimRight(buf.Bytes(), "\n"), &got); err != nil {
+		t.Fatalf("unmarshaling json: %v", err)
+	}
+
+	want := logEvent{Timestamp: ts, LogStream: "2024/06/01/[$LATEST]abc123", Message: "START RequestId: abc123"}
+	if !got.Timestamp.Equal(want.Timestamp) || got.LogStream != want.LogStream || got.Message != want.Message {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
@@ -2,16 +2,15 @@ package main
 
 import (
 	"context"
-	"encoding/csv"
 	"flag"
 	"fmt"
 	"os"
 	"reflect"
-	"sync"
+	"strconv"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
@@ -23,26 +22,56 @@ import (
 type settings struct {
 	showDebugLog   bool
 	awsProfileName string
+	regions        string
 	outputFileName string
+	format         string
+	concurrency    int
+	window         time.Duration
+	filter         string
+	stale          time.Duration
 }
 
 // application will hold all the dependencies that wil be used in many functions
 type application struct {
-	logger       *zap.Logger
-	lambdaClient *lambda.Client
-	cwlogsClient *cloudwatchlogs.Client
+	logger          *zap.Logger
+	lambdaClient    *lambda.Client
+	cwlogsClient    *cloudwatchlogs.Client
+	cwMetricsClient *cloudwatch.Client
 }
 
-// lambdaFunctionDetails holds the details of the lambda function that will be printed
-// the title tag is the title of the column of the resulting CSV file
+// lambdaFunctionDetails holds the details of the lambda function that will be printed.
+// The title tag is the title of the column in tabular output (CSV/Markdown); the json
+// tag controls the key used by the JSON/JSONL writers, and the parquet tag the column
+// used by the Parquet writer.
 type lambdaFunctionDetails struct {
-	name         string `title:"Function Name"`
-	arn          string `title:"Function ARN"`
-	description  string `title:"Function Description"`
-	lastModified string `title:"Last Modified"`
-	iamRole      string `title:"IAM Role"`
-	runtime      string `title:"Runtime"`
-	lastInvoked  string `title:"Last Invoked"`
+	Name         string `title:"Function Name" json:"name" parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ARN          string `title:"Function ARN" json:"arn" parquet:"name=arn, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Description  string `title:"Function Description" json:"description" parquet:"name=description, type=BYTE_ARRAY, convertedtype=UTF8"`
+	LastModified string `title:"Last Modified" json:"lastModified" parquet:"name=lastModified, type=BYTE_ARRAY, convertedtype=UTF8"`
+	IAMRole      string `title:"IAM Role" json:"iamRole" parquet:"name=iamRole, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Runtime      string `title:"Runtime" json:"runtime" parquet:"name=runtime, type=BYTE_ARRAY, convertedtype=UTF8"`
+	LastInvoked  string `title:"Last Invoked" json:"lastInvoked" parquet:"name=lastInvoked, type=BYTE_ARRAY, convertedtype=UTF8"`
+	AccountID    string `title:"AWS Account ID" json:"accountId" parquet:"name=accountId, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Profile      string `title:"Profile" json:"profile" parquet:"name=profile, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Region       string `title:"Region" json:"region" parquet:"name=region, type=BYTE_ARRAY, convertedtype=UTF8"`
+
+	MemorySize      string `title:"Memory Size (MB)" json:"memorySize" parquet:"name=memorySize, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Timeout         string `title:"Timeout (s)" json:"timeout" parquet:"name=timeout, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CodeSize        string `title:"Code Size (Bytes)" json:"codeSize" parquet:"name=codeSize, type=BYTE_ARRAY, convertedtype=UTF8"`
+	PackageType     string `title:"Package Type" json:"packageType" parquet:"name=packageType, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Architectures   string `title:"Architectures" json:"architectures" parquet:"name=architectures, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Handler         string `title:"Handler" json:"handler" parquet:"name=handler, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Layers          string `title:"Layers" json:"layers" parquet:"name=layers, type=BYTE_ARRAY, convertedtype=UTF8"`
+	DeadLetterArn   string `title:"Dead Letter Target ARN" json:"deadLetterArn" parquet:"name=deadLetterArn, type=BYTE_ARRAY, convertedtype=UTF8"`
+	TracingMode     string `title:"Tracing Mode" json:"tracingMode" parquet:"name=tracingMode, type=BYTE_ARRAY, convertedtype=UTF8"`
+	VpcID           string `title:"VPC ID" json:"vpcId" parquet:"name=vpcId, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Tags            string `title:"Tags" json:"tags" parquet:"name=tags, type=BYTE_ARRAY, convertedtype=UTF8"`
+
+	InvocationsSum string `title:"Invocations" json:"invocationsSum" parquet:"name=invocationsSum, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ErrorsSum      string `title:"Errors" json:"errorsSum" parquet:"name=errorsSum, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ThrottlesSum   string `title:"Throttles" json:"throttlesSum" parquet:"name=throttlesSum, type=BYTE_ARRAY, convertedtype=UTF8"`
+	DurationP50Ms  string `title:"Duration p50 (ms)" json:"durationP50Ms" parquet:"name=durationP50Ms, type=BYTE_ARRAY, convertedtype=UTF8"`
+	DurationP95Ms  string `title:"Duration p95 (ms)" json:"durationP95Ms" parquet:"name=durationP95Ms, type=BYTE_ARRAY, convertedtype=UTF8"`
 }
 
 // getTitleFields will return a list of strings that is populated by the struct title tag
@@ -60,35 +89,52 @@ func (l lambdaFunctionDetails) getTitleFields() []string {
 
 const lambdaLogGroupPrefix = "/aws/lambda/"
 
+// usage is printed when the tool is invoked without a recognized subcommand.
+const usage = "usage: aws-lambda-lister <list|logs> [flags]"
+
 func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	switch os.Args[1] {
+	case "list":
+		runList(os.Args[2:])
+	case "logs":
+		runLogs(os.Args[2:])
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+}
+
+func runList(args []string) {
 	var stg settings
-	flag.BoolVar(&stg.showDebugLog, "show-debug-log", false, "Show debug log")
-	flag.StringVar(&stg.awsProfileName, "aws-profile", "default", "AWS Profile Name")
-	flag.StringVar(&stg.outputFileName, "out-name", "lambda-list.csv", "The name of the output file")
-	flag.Parse()
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	fs.BoolVar(&stg.showDebugLog, "show-debug-log", false, "Show debug log")
+	fs.StringVar(&stg.awsProfileName, "aws-profile", "default", "Comma-separated list of AWS profile names to enumerate")
+	fs.StringVar(&stg.regions, "regions", "", "Comma-separated list of AWS regions to enumerate (or \"all\"); defaults to each profile's own region")
+	fs.StringVar(&stg.outputFileName, "out-name", "lambda-list.csv", "The name of the output file")
+	fs.StringVar(&stg.format, "format", "csv", "Output format: csv, json, jsonl, md, or parquet")
+	fs.IntVar(&stg.concurrency, "concurrency", 8, "Number of concurrent workers used to look up last-invoke times and tags")
+	stg.window = 30 * 24 * time.Hour
+	fs.Var((*dayDuration)(&stg.window), "window", "Lookback window for CloudWatch invocation metrics, e.g. 30d")
+	fs.StringVar(&stg.filter, "filter", "", `Filter expression, e.g. runtime=~"python3\..*" && memory>512 && tag:Env=="prod"`)
+	fs.Var((*dayDuration)(&stg.stale), "stale", "Shorthand for functions whose last invoke time is older than this duration (or Not Found/Error), e.g. 90d")
+	fs.Parse(args)
 
 	logger := createLogger(stg.showDebugLog)
 	defer logger.Sync()
 
-	logger.Debug("loading default config")
-	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithSharedConfigProfile(stg.awsProfileName))
-	if err != nil {
-		logger.Fatal("error when loading default config",
-			zap.Error(err),
-		)
-	}
-
-	lambdaClient := lambda.NewFromConfig(cfg)
-	cwlogsClient := cloudwatchlogs.NewFromConfig(cfg)
+	ctx := context.Background()
+	targets := buildTargets(stg.awsProfileName, stg.regions)
 
-	app := &application{
-		logger:       logger,
-		lambdaClient: lambdaClient,
-		cwlogsClient: cwlogsClient,
-	}
-
-	logger.Info("getting function details for all lambda functions")
-	lambdaFunctionsDetailsList, err := app.getAllLambdaFunctionsDetailsList()
+	logger.Info("getting function details for all lambda functions",
+		zap.Int("targets", len(targets)),
+	)
+	opts := enrichmentOptions{concurrency: stg.concurrency, window: stg.window}
+	lambdaFunctionsDetailsList, err := getAllLambdaFunctionsAcrossTargets(ctx, logger, targets, opts)
 	if err != nil {
 		logger.Fatal("error when listing lambda function details",
 			zap.Error(err),
@@ -98,10 +144,23 @@ func main() {
 		zap.Int("length", len(lambdaFunctionsDetailsList)),
 	)
 
-	logger.Info("getting last invoke time for all lambda functions")
-	wg := &sync.WaitGroup{}
-	app.getAllLambdaFunctionsLastInvokeTimeBackground(lambdaFunctionsDetailsList, wg)
-	wg.Wait()
+	filterExpr, err := buildFilterExpr(stg.filter, stg.stale)
+	if err != nil {
+		logger.Fatal("error when parsing -filter/-stale",
+			zap.Error(err),
+		)
+	}
+	if filterExpr != nil {
+		lambdaFunctionsDetailsList, err = filterFunctions(lambdaFunctionsDetailsList, filterExpr)
+		if err != nil {
+			logger.Fatal("error when applying filter",
+				zap.Error(err),
+			)
+		}
+		logger.Debug("applied filter",
+			zap.Int("length", len(lambdaFunctionsDetailsList)),
+		)
+	}
 
 	logger.Sugar().Infof("writing the output to %s", stg.outputFileName)
 	f, err := os.Create(stg.outputFileName)
@@ -112,24 +171,17 @@ func main() {
 	}
 	defer f.Close()
 
-	w := csv.NewWriter(f)
-	defer w.Flush()
-
-	titles := lambdaFunctionsDetailsList[0].getTitleFields()
-	err = w.Write(titles)
+	out, err := newWriter(stg.format, f)
 	if err != nil {
-		logger.Error("error when writing title")
+		logger.Fatal("error when creating output writer",
+			zap.Error(err),
+		)
 	}
 
-	for _, lambdaDetails := range lambdaFunctionsDetailsList {
-		record := []string{lambdaDetails.name, lambdaDetails.arn, lambdaDetails.description, lambdaDetails.lastModified, lambdaDetails.iamRole, lambdaDetails.runtime, lambdaDetails.lastInvoked}
-		err := w.Write(record)
-		if err != nil {
-			logger.Error("error when writing the entry",
-				zap.String("function_name", lambdaDetails.name),
-				zap.Error(err),
-			)
-		}
+	if err := out.Write(lambdaFunctionsDetailsList); err != nil {
+		logger.Error("error when writing the output",
+			zap.Error(err),
+		)
 	}
 
 	logger.Info("all the function details have been written to the output",
@@ -150,12 +202,23 @@ func (app *application) getAllLambdaFunctionsDetailsList() ([]lambdaFunctionDeta
 
 		for _, functionDetail := range out.Functions {
 			l := lambdaFunctionDetails{
-				name:         *functionDetail.FunctionName,
-				arn:          *functionDetail.FunctionArn,
-				description:  *functionDetail.Description,
-				lastModified: *functionDetail.LastModified,
-				iamRole:      *functionDetail.Role,
-				runtime:      string(functionDetail.Runtime),
+				Name:         *functionDetail.FunctionName,
+				ARN:          *functionDetail.FunctionArn,
+				Description:  *functionDetail.Description,
+				LastModified: *functionDetail.LastModified,
+				IAMRole:      *functionDetail.Role,
+				Runtime:      string(functionDetail.Runtime),
+
+				MemorySize:    formatInt32(functionDetail.MemorySize),
+				Timeout:       formatInt32(functionDetail.Timeout),
+				CodeSize:      strconv.FormatInt(functionDetail.CodeSize, 10),
+				PackageType:   string(functionDetail.PackageType),
+				Architectures: joinArchitectures(functionDetail.Architectures),
+				Handler:       aws.ToString(functionDetail.Handler),
+				Layers:        joinLayers(functionDetail.Layers),
+				DeadLetterArn: deadLetterTargetArn(functionDetail.DeadLetterConfig),
+				TracingMode:   tracingMode(functionDetail.TracingConfig),
+				VpcID:         vpcID(functionDetail.VpcConfig),
 			}
 
 			lambdaFunctionsDetailsList = append(lambdaFunctionsDetailsList, l)
@@ -172,8 +235,12 @@ func (app *application) getAllLambdaFunctionsDetailsList() ([]lambdaFunctionDeta
 	return lambdaFunctionsDetailsList, nil
 }
 
-func (app *application) getLambdaFunctionLastInvokeTimeBackground(functionName string, index int, outputList []lambdaFunctionDetails, wg *sync.WaitGroup) {
-	defer wg.Done()
+// getLambdaFunctionLastInvokeTime looks up the last invoke time for a single function,
+// retrying with backoff on CloudWatch Logs throttling and writing the result directly
+// into outputList[index]. Errors other than throttling (exhausted retries included) are
+// surfaced as "Error" so they can be told apart from a function that has genuinely never
+// been invoked ("Not Found").
+func (app *application) getLambdaFunctionLastInvokeTime(ctx context.Context, functionName string, index int, outputList []lambdaFunctionDetails) {
 	logGroupName := fmt.Sprintf("%s%s", lambdaLogGroupPrefix, functionName)
 
 	input := &cloudwatchlogs.DescribeLogStreamsInput{
@@ -183,39 +250,51 @@ func (app *application) getLambdaFunctionLastInvokeTimeBackground(functionName s
 		OrderBy:      types.OrderByLastEventTime,
 	}
 
-	out, err := app.cwlogsClient.DescribeLogStreams(context.Background(), input)
+	var out *cloudwatchlogs.DescribeLogStreamsOutput
+	err := withRetry(ctx, defaultRetryConfig, func() error {
+		var callErr error
+		out, callErr = app.cwlogsClient.DescribeLogStreams(ctx, input)
+		return callErr
+	})
+
 	if err != nil {
 		app.logger.Debug("error when describing log stream",
 			zap.Error(err),
 			zap.String("log group name", logGroupName),
 		)
+		outputList[index].LastInvoked = "Error"
+		return
 	}
 
-	if out != nil && out.LogStreams != nil && out.LogStreams[0].LastEventTimestamp != nil {
+	if out != nil && len(out.LogStreams) > 0 && out.LogStreams[0].LastEventTimestamp != nil {
 		lastEventTimestampInSeconds := *out.LogStreams[0].LastEventTimestamp / 1000
 		t := time.Unix(lastEventTimestampInSeconds, 0)
 
-		outputList[index].lastInvoked = t.Format("2006-01-02T15:04:05-07:00")
+		outputList[index].LastInvoked = t.Format("2006-01-02T15:04:05-07:00")
 		app.logger.Debug("last invoke time info",
 			zap.Int64("*out.LogStreams[0].LastEventTimestamp", *out.LogStreams[0].LastEventTimestamp/1000),
 			zap.Int64("lastEventTimestampInSeconds", lastEventTimestampInSeconds),
 			zap.String("formatted time", t.Format("2006-01-02T15:04:05-07:00")),
-			zap.String("outputList[index].lastInvoked", outputList[index].lastInvoked),
+			zap.String("outputList[index].LastInvoked", outputList[index].LastInvoked),
 		)
 	} else {
 		app.logger.Debug("cannot find the last invoke time for lambda",
 			zap.String("function_name", functionName),
 		)
 
-		outputList[index].lastInvoked = "Not Found"
+		outputList[index].LastInvoked = "Not Found"
 	}
 }
 
-func (app *application) getAllLambdaFunctionsLastInvokeTimeBackground(outputlist []lambdaFunctionDetails, wg *sync.WaitGroup) {
-	for i, lambdaDetails := range outputlist {
-		wg.Add(1)
-		go app.getLambdaFunctionLastInvokeTimeBackground(lambdaDetails.name, i, outputlist, wg)
-	}
+// getAllLambdaFunctionsLastInvokeTime looks up the last invoke time for every function in
+// outputList, bounded to concurrency concurrent workers sharing a token-bucket rate
+// limiter so the account's CloudWatch Logs DescribeLogStreams TPS ceiling isn't tripped.
+func (app *application) getAllLambdaFunctionsLastInvokeTime(ctx context.Context, outputList []lambdaFunctionDetails, concurrency int) {
+	pool := newWorkerPool(ctx, concurrency, cloudWatchLogsTPSLimit)
+
+	pool.run(ctx, len(outputList), func(ctx context.Context, index int) {
+		app.getLambdaFunctionLastInvokeTime(ctx, outputList[index].Name, index, outputList)
+	})
 }
 
 func createLogger(showDebug bool) *zap.Logger {
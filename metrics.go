@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// metricsQueriesPerFunction is how many GetMetricData queries are issued per function:
+// Invocations (Sum), Errors (Sum), Throttles (Sum), Duration (p50), Duration (p95).
+const metricsQueriesPerFunction = 5
+
+// metricsBatchSize is the maximum number of MetricDataQuery entries GetMetricData accepts
+// in a single call.
+const metricsBatchSize = 500
+
+// getAllLambdaFunctionsMetrics pulls AWS/Lambda Invocations, Errors, Throttles, and
+// Duration (p50/p95) for every function in outputList over the given window, batching
+// GetMetricData calls at metricsBatchSize queries at a time.
+func (app *application) getAllLambdaFunctionsMetrics(ctx context.Context, outputList []lambdaFunctionDetails, window time.Duration) error {
+	if len(outputList) == 0 {
+		return nil
+	}
+
+	endTime := time.Now()
+	startTime := endTime.Add(-window)
+	period := metricsPeriod(window)
+
+	functionsPerBatch := metricsBatchSize / metricsQueriesPerFunction
+	if functionsPerBatch < 1 {
+		functionsPerBatch = 1
+	}
+
+	for batchStart := 0; batchStart < len(outputList); batchStart += functionsPerBatch {
+		batchEnd := batchStart + functionsPerBatch
+		if batchEnd > len(outputList) {
+			batchEnd = len(outputList)
+		}
+		batch := outputList[batchStart:batchEnd]
+
+		out, err := app.cwMetricsClient.GetMetricData(ctx, &cloudwatch.GetMetricDataInput{
+			MetricDataQueries: buildMetricDataQueries(batch, period),
+			StartTime:         aws.Time(startTime),
+			EndTime:           aws.Time(endTime),
+		})
+		if err != nil {
+			return fmt.Errorf("getting metric data: %w", err)
+		}
+
+		applyMetricResults(batch, out.MetricDataResults)
+	}
+
+	return nil
+}
+
+// metricsPeriod picks a single CloudWatch period covering the whole window, rounded up to
+// the nearest minute (CloudWatch periods must be a multiple of 60 seconds).
+func metricsPeriod(window time.Duration) int32 {
+	seconds := int32((window + time.Minute - 1).Truncate(time.Minute).Seconds())
+	if seconds < 60 {
+		seconds = 60
+	}
+	return seconds
+}
+
+func buildMetricDataQueries(batch []lambdaFunctionDetails, period int32) []types.MetricDataQuery {
+	var queries []types.MetricDataQuery
+
+	for i, fn := range batch {
+		dims := []types.Dimension{{Name: aws.String("FunctionName"), Value: aws.String(fn.Name)}}
+
+		queries = append(queries,
+			metricDataQuery(i, "invocations", "Invocations", "Sum", period, dims),
+			metricDataQuery(i, "errors", "Errors", "Sum", period, dims),
+			metricDataQuery(i, "throttles", "Throttles", "Sum", period, dims),
+			metricDataQuery(i, "p50", "Duration", "p50", period, dims),
+			metricDataQuery(i, "p95", "Duration", "p95", period, dims),
+		)
+	}
+
+	return queries
+}
+
+// metricDataQuery builds a single query whose Id encodes the function's index within the
+// batch and the metric it's for, so the result can be routed back in applyMetricResults.
+func metricDataQuery(index int, key, metricName, stat string, period int32, dims []types.Dimension) types.MetricDataQuery {
+	return types.MetricDataQuery{
+		Id: aws.String(fmt.Sprintf("m%d_%s", index, key)),
+		MetricStat: &types.MetricStat{
+			Metric: &types.Metric{
+				Namespace:  aws.String("AWS/Lambda"),
+				MetricName: aws.String(metricName),
+				Dimensions: dims,
+			},
+			Period: aws.Int32(period),
+			Stat:   aws.String(stat),
+		},
+	}
+}
+
+func applyMetricResults(batch []lambdaFunctionDetails, results []types.MetricDataResult) {
+	for _, result := range results {
+		index, key, ok := parseMetricQueryID(aws.ToString(result.Id))
+		if !ok || index < 0 || index >= len(batch) {
+			continue
+		}
+
+		value, hasValue := firstMetricValue(result.Values)
+		if !hasValue {
+			continue
+		}
+
+		switch key {
+		case "invocations":
+			batch[index].InvocationsSum = strconv.FormatFloat(value, 'f', 0, 64)
+		case "errors":
+			batch[index].ErrorsSum = strconv.FormatFloat(value, 'f', 0, 64)
+		case "throttles":
+			batch[index].ThrottlesSum = strconv.FormatFloat(value, 'f', 0, 64)
+		case "p50":
+			batch[index].DurationP50Ms = strconv.FormatFloat(value, 'f', 2, 64)
+		case "p95":
+			batch[index].DurationP95Ms = strconv.FormatFloat(value, 'f', 2, 64)
+		}
+	}
+}
+
+func parseMetricQueryID(id string) (index int, key string, ok bool) {
+	id = strings.TrimPrefix(id, "m")
+	parts := strings.SplitN(id, "_", 2)
+	if len(parts) != 2 {
+		return 0, "", false
+	}
+
+	index, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", false
+	}
+
+	return index, parts[1], true
+}
+
+func firstMetricValue(values []float64) (float64, bool) {
+	if len(values) == 0 {
+		return 0, false
+	}
+	return values[0], true
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+func TestMetricsPeriod(t *testing.T) {
+	tests := []struct {
+		window time.Duration
+		want   int32
+	}{
+		{30 * time.Second, 60},
+		{time.Minute, 60},
+		{90 * time.Second, 120},
+		{30 * 24 * time.Hour, 2592000},
+	}
+
+	for _, tt := range tests {
+		if got := metricsPeriod(tt.window); got != tt.want {
+			t.Fatalf("metricsPeriod(%v) = %d, want %d", tt.window, got, tt.want)
+		}
+	}
+}
+
+func TestBuildMetricDataQueries(t *testing.T) {
+	batch := []lambdaFunctionDetails{{Name: "fn-a"}, {Name: "fn-b"}}
+	queries := buildMetricDataQueries(batch, 60)
+
+	if len(queries) != len(batch)*metricsQueriesPerFunction {
+		t.Fatalf("got %d queries, want %d", len(queries), len(batch)*metricsQueriesPerFunction)
+	}
+
+	wantIDs := []string{
+		"m0_invocations", "m0_errors", "m0_throttles", "m0_p50", "m0_p95",
+		"m1_invocations", "m1_errors", "m1_throttles", "m1_p50", "m1_p95",
+	}
+	for i, q := range queries {
+		if got := aws.ToString(q.Id); got != wantIDs[i] {
+			t.Fatalf("query %d Id = %q, want %q", i, got, wantIDs[i])
+		}
+	}
+
+	if got := aws.ToString(queries[0].MetricStat.Metric.Dimensions[0].Value); got != "fn-a" {
+		t.Fatalf("query 0 FunctionName dimension = %q, want %q", got, "fn-a")
+	}
+}
+
+func TestParseMetricQueryID(t *testing.T) {
+	tests := []struct {
+		id        string
+		wantIndex int
+		wantKey   string
+		wantOK    bool
+	}{
+		{"m0_invocations", 0, "invocations", true},
+		{"m12_p95", 12, "p95", true},
+		{"malformed", 0, "", false},
+		{"mNaN_errors", 0, "", false},
+	}
+
+	for _, tt := range tests {
+		index, key, ok := parseMetricQueryID(tt.id)
+		if ok != tt.wantOK || (ok && (index != tt.wantIndex || key != tt.wantKey)) {
+			t.Fatalf("parseMetricQueryID(%q) = (%d, %q, %v), want (%d, %q, %v)", tt.id, index, key, ok, tt.wantIndex, tt.wantKey, tt.wantOK)
+		}
+	}
+}
+
+func TestFirstMetricValue(t *testing.T) {
+	if _, ok := firstMetricValue(nil); ok {
+		t.Fatal("firstMetricValue(nil) reported a value, want ok=false")
+	}
+	v, ok := firstMetricValue([]float64{42.5, 1})
+	if !ok || v != 42.5 {
+		t.Fatalf("firstMetricValue(...) = (%v, %v), want (42.5, true)", v, ok)
+	}
+}
+
+func TestApplyMetricResults(t *testing.T) {
+	batch := []lambdaFunctionDetails{{Name: "fn-a"}, {Name: "fn-b"}}
+
+	results := []types.MetricDataResult{
+		{Id: aws.String("m0_invocations"), Values: []float64{10}},
+		{Id: aws.String("m0_errors"), Values: []float64{2}},
+		{Id: aws.String("m0_throttles"), Values: []float64{0}},
+		{Id: aws.String("m0_p50"), Values: []float64{12.3}},
+		{Id: aws.String("m0_p95"), Values: []float64{45.6}},
+		{Id: aws.String("m1_invocations"), Values: nil},
+		{Id: aws.String("unrecognized")},
+		{Id: aws.String("m5_invocations"), Values: []float64{1}},
+	}
+
+	applyMetricResults(batch, results)
+
+	want := lambdaFunctionDetails{
+		Name:           "fn-a",
+		InvocationsSum: "10",
+		ErrorsSum:      "2",
+		ThrottlesSum:   "0",
+		DurationP50Ms:  "12.30",
+		DurationP95Ms:  "45.60",
+	}
+	if !reflect.DeepEqual(batch[0], want) {
+		t.Fatalf("batch[0] = %+v, want %+v", batch[0], want)
+	}
+
+	if batch[1] != (lambdaFunctionDetails{Name: "fn-b"}) {
+		t.Fatalf("batch[1] = %+v, want untouched (no values for m1_invocations)", batch[1])
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/xitongsys/parquet-go-source/writerfile"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// OutputWriter renders a full list of lambdaFunctionDetails to an underlying io.Writer in
+// a specific format.
+type OutputWriter interface {
+	// Write renders the full list of lambda function details. It is called exactly once
+	// with the complete list and is responsible for flushing/finalizing any buffered
+	// output before returning.
+	Write(list []lambdaFunctionDetails) error
+}
+
+// newWriter builds the OutputWriter for the given format, writing to w. Supported formats
+// are "csv" (the default), "json", "jsonl", "md", and "parquet".
+func newWriter(format string, w io.Writer) (OutputWriter, error) {
+	switch format {
+	case "", "csv":
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case "json":
+		return &jsonWriter{w: w}, nil
+	case "jsonl":
+		return &jsonlWriter{w: w}, nil
+	case "md":
+		return &markdownWriter{w: w}, nil
+	case "parquet":
+		ws, ok := w.(io.WriteSeeker)
+		if !ok {
+			return nil, fmt.Errorf("parquet output requires a seekable destination (e.g. a file), got %T", w)
+		}
+		return &parquetWriter{w: ws}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output format %q", format)
+	}
+}
+
+// recordFields returns the string representation of every field in l, in struct
+// declaration order, matching the order returned by getTitleFields.
+func recordFields(l lambdaFunctionDetails) []string {
+	value := reflect.ValueOf(l)
+	fields := make([]string, value.NumField())
+	for i := range fields {
+		fields[i] = fmt.Sprintf("%v", value.Field(i).Interface())
+	}
+	return fields
+}
+
+// csvWriter renders the list as CSV, titles first, same as the tool has always produced.
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (cw *csvWriter) Write(list []lambdaFunctionDetails) error {
+	if len(list) == 0 {
+		return nil
+	}
+
+	if err := cw.w.Write(list[0].getTitleFields()); err != nil {
+		return fmt.Errorf("writing title: %w", err)
+	}
+
+	for _, details := range list {
+		if err := cw.w.Write(recordFields(details)); err != nil {
+			return fmt.Errorf("writing record for %s: %w", details.Name, err)
+		}
+	}
+
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// jsonWriter renders the list as a single indented JSON array.
+type jsonWriter struct {
+	w io.Writer
+}
+
+func (jw *jsonWriter) Write(list []lambdaFunctionDetails) error {
+	enc := json.NewEncoder(jw.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(list)
+}
+
+// jsonlWriter renders the list as newline-delimited JSON, one object per function, for
+// piping into jq or log pipelines.
+type jsonlWriter struct {
+	w io.Writer
+}
+
+func (jl *jsonlWriter) Write(list []lambdaFunctionDetails) error {
+	enc := json.NewEncoder(jl.w)
+	for _, details := range list {
+		if err := enc.Encode(details); err != nil {
+			return fmt.Errorf("writing record for %s: %w", details.Name, err)
+		}
+	}
+	return nil
+}
+
+// markdownWriter renders the list as a GitHub-flavored Markdown table, for pasting into
+// tickets/runbooks.
+type markdownWriter struct {
+	w io.Writer
+}
+
+func (mw *markdownWriter) Write(list []lambdaFunctionDetails) error {
+	if len(list) == 0 {
+		return nil
+	}
+
+	bw := bufio.NewWriter(mw.w)
+
+	titles := list[0].getTitleFields()
+	fmt.Fprintf(bw, "| %s |\n", strings.Join(titles, " | "))
+	fmt.Fprintf(bw, "| %s |\n", strings.Join(markdownHeaderSeparator(len(titles)), " | "))
+
+	for _, details := range list {
+		cells := recordFields(details)
+		for i, cell := range cells {
+			cells[i] = escapeMarkdownCell(cell)
+		}
+		fmt.Fprintf(bw, "| %s |\n", strings.Join(cells, " | "))
+	}
+
+	return bw.Flush()
+}
+
+func markdownHeaderSeparator(numColumns int) []string {
+	cells := make([]string, numColumns)
+	for i := range cells {
+		cells[i] = "---"
+	}
+	return cells
+}
+
+// markdownNewlineReplacer collapses any line breaks in a cell value into spaces, since a
+// literal newline would otherwise end the Markdown table row early.
+var markdownNewlineReplacer = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+// escapeMarkdownCell makes s safe to place inside a GitHub-flavored Markdown table cell:
+// a literal "|" would otherwise be read as a column separator, and a newline would break
+// the row.
+func escapeMarkdownCell(s string) string {
+	s = markdownNewlineReplacer.Replace(s)
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// parquetWriter renders the list as a Parquet file, for loading straight into
+// Athena/BigQuery for fleet analytics. Parquet's footer-writing requires a seekable
+// destination, so unlike the other writers this one needs an io.WriteSeeker (a file).
+type parquetWriter struct {
+	w io.WriteSeeker
+}
+
+func (pw *parquetWriter) Write(list []lambdaFunctionDetails) error {
+	fw := writerfile.NewWriterFile(pw.w)
+
+	pf, err := writer.NewParquetWriter(fw, new(lambdaFunctionDetails), 4)
+	if err != nil {
+		return fmt.Errorf("creating parquet writer: %w", err)
+	}
+
+	for _, details := range list {
+		if err := pf.Write(details); err != nil {
+			return fmt.Errorf("writing record for %s: %w", details.Name, err)
+		}
+	}
+
+	if err := pf.WriteStop(); err != nil {
+		return fmt.Errorf("finishing parquet file: %w", err)
+	}
+
+	return nil
+}
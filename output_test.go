@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func fixtureLambdaFunctionDetailsList() []lambdaFunctionDetails {
+	return []lambdaFunctionDetails{
+		{
+			Name:         "my-function",
+			ARN:          "arn:aws:lambda:us-east-1:123456789012:function:my-function",
+			Description:  "does a thing",
+			LastModified: "2024-01-02T15:04:05+00:00",
+			IAMRole:      "arn:aws:iam::123456789012:role/my-role",
+			Runtime:      "python3.12",
+			LastInvoked:  "2024-06-01T00:00:00+00:00",
+			AccountID:    "123456789012",
+			Profile:      "default",
+			Region:       "us-east-1",
+		},
+		{
+			Name:         "another-function",
+			ARN:          "arn:aws:lambda:eu-west-1:123456789012:function:another-function",
+			Description:  "",
+			LastModified: "2023-11-20T10:00:00+00:00",
+			IAMRole:      "arn:aws:iam::123456789012:role/another-role",
+			Runtime:      "nodejs20.x",
+			LastInvoked:  "Not Found",
+			AccountID:    "123456789012",
+			Profile:      "prod",
+			Region:       "eu-west-1",
+		},
+	}
+}
+
+func TestNewWriterUnsupportedFormat(t *testing.T) {
+	if _, err := newWriter("yaml", &bytes.Buffer{}); err == nil {
+		t.Fatal("expected an error for an unsupported format, got nil")
+	}
+}
+
+func TestNewWriterParquetRequiresSeekable(t *testing.T) {
+	if _, err := newWriter("parquet", &bytes.Buffer{}); err == nil {
+		t.Fatal("expected an error for a non-seekable parquet destination, got nil")
+	}
+}
+
+func TestParquetWriterRoundTrip(t *testing.T) {
+	list := fixtureLambdaFunctionDetailsList()
+
+	f, err := os.CreateTemp(t.TempDir(), "lambda-list-*.parquet")
+	if err != nil {
+		t.Fatalf("creating temp file: %v", err)
+	}
+	defer f.Close()
+
+	w, err := newWriter("parquet", f)
+	if err != nil {
+		t.Fatalf("newWriter: %v", err)
+	}
+	if err := w.Write(list); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatalf("stat temp file: %v", err)
+	}
+	if info.Size() == 0 {
+		t.Fatal("expected a non-empty parquet file")
+	}
+}
+
+func TestCSVWriterRoundTrip(t *testing.T) {
+	list := fixtureLambdaFunctionDetailsList()
+
+	var buf bytes.Buffer
+	w, err := newWriter("csv", &buf)
+	if err != nil {
+		t.Fatalf("newWriter: %v", err)
+	}
+	if err := w.Write(list); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("reading back csv: %v", err)
+	}
+	if len(records) != len(list)+1 {
+		t.Fatalf("got %d records, want %d (including title row)", len(records), len(list)+1)
+	}
+	if records[0][0] != "Function Name" {
+		t.Fatalf("got title %q, want %q", records[0][0], "Function Name")
+	}
+	if records[1][0] != list[0].Name {
+		t.Fatalf("got name %q, want %q", records[1][0], list[0].Name)
+	}
+}
+
+func TestJSONWriterRoundTrip(t *testing.T) {
+	list := fixtureLambdaFunctionDetailsList()
+
+	var buf bytes.Buffer
+	w, err := newWriter("json", &buf)
+	if err != nil {
+		t.Fatalf("newWriter: %v", err)
+	}
+	if err := w.Write(list); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	var got []lambdaFunctionDetails
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshaling json: %v", err)
+	}
+	if len(got) != len(list) {
+		t.Fatalf("got %d records, want %d", len(got), len(list))
+	}
+	if got[0] != list[0] {
+		t.Fatalf("got %+v, want %+v", got[0], list[0])
+	}
+}
+
+func TestJSONLWriterRoundTrip(t *testing.T) {
+	list := fixtureLambdaFunctionDetailsList()
+
+	var buf bytes.Buffer
+	w, err := newWriter("jsonl", &buf)
+	if err != nil {
+		t.Fatalf("newWriter: %v", err)
+	}
+	if err := w.Write(list); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != len(list) {
+		t.Fatalf("got %d lines, want %d", len(lines), len(list))
+	}
+	for i, line := range lines {
+		var got lambdaFunctionDetails
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("unmarshaling line %d: %v", i, err)
+		}
+		if got != list[i] {
+			t.Fatalf("line %d: got %+v, want %+v", i, got, list[i])
+		}
+	}
+}
+
+func TestMarkdownWriterRoundTrip(t *testing.T) {
+	list := fixtureLambdaFunctionDetailsList()
+
+	var buf bytes.Buffer
+	w, err := newWriter("md", &buf)
+	if err != nil {
+		t.Fatalf("newWriter: %v", err)
+	}
+	if err := w.Write(list); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != len(list)+2 {
+		t.Fatalf("got %d lines, want %d (header, separator, rows)", len(lines), len(list)+2)
+	}
+	if !strings.Contains(lines[0], "Function Name") {
+		t.Fatalf("header row %q missing title", lines[0])
+	}
+	if !strings.Contains(lines[2], list[0].Name) {
+		t.Fatalf("row %q missing name %q", lines[2], list[0].Name)
+	}
+}
+
+func TestMarkdownWriterEscapesCells(t *testing.T) {
+	list := []lambdaFunctionDetails{
+		{Name: "my-function", Description: "does a thing | has a pipe\nand a newline"},
+	}
+
+	var buf bytes.Buffer
+	w, err := newWriter("md", &buf)
+	if err != nil {
+		t.Fatalf("newWriter: %v", err)
+	}
+	if err := w.Write(list); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != len(list)+2 {
+		t.Fatalf("got %d lines, want %d (header, separator, rows); embedded \"|\"/newline broke the table: %q", len(lines), len(list)+2, buf.String())
+	}
+	if !strings.Contains(lines[2], `does a thing \| has a pipe and a newline`) {
+		t.Fatalf("row %q did not escape the pipe and collapse the newline", lines[2])
+	}
+}
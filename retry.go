@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// retryConfig controls the retry/backoff behaviour applied to throttled CloudWatch Logs
+// calls, mirroring the max-attempts/initial-interval/backoff-factor/max-interval retrier
+// used by Step Functions task retriers.
+type retryConfig struct {
+	maxAttempts     int
+	initialInterval time.Duration
+	backoffFactor   float64
+	maxInterval     time.Duration
+}
+
+// defaultRetryConfig is used for all CloudWatch Logs lookups performed by the worker pool.
+var defaultRetryConfig = retryConfig{
+	maxAttempts:     5,
+	initialInterval: 200 * time.Millisecond,
+	backoffFactor:   2.0,
+	maxInterval:     10 * time.Second,
+}
+
+// isThrottlingError reports whether err is a CloudWatch Logs throttling error that should
+// be retried with backoff rather than surfaced to the caller immediately.
+func isThrottlingError(err error) bool {
+	var throttling *types.ThrottlingException
+	var limitExceeded *types.LimitExceededException
+	return errors.As(err, &throttling) || errors.As(err, &limitExceeded)
+}
+
+// withRetry calls fn, retrying with jittered exponential backoff while fn's error is a
+// throttling error, up to cfg.maxAttempts attempts. Non-throttling errors are returned
+// immediately.
+func withRetry(ctx context.Context, cfg retryConfig, fn func() error) error {
+	interval := cfg.initialInterval
+	var err error
+
+	for attempt := 1; attempt <= cfg.maxAttempts; attempt++ {
+		err = fn()
+		if err == nil || !isThrottlingError(err) {
+			return err
+		}
+
+		if attempt == cfg.maxAttempts {
+			break
+		}
+
+		jitter := time.Duration(rand.Int63n(int64(interval) + 1))
+		wait := interval/2 + jitter/2
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+
+		interval = time.Duration(float64(interval) * cfg.backoffFactor)
+		if interval > cfg.maxInterval {
+			interval = cfg.maxInterval
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+func TestIsThrottlingError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errors.New("boom"), false},
+		{"throttling exception", &types.ThrottlingException{}, true},
+		{"limit exceeded exception", &types.LimitExceededException{}, true},
+		{"wrapped throttling exception", fmt.Errorf("calling DescribeLogStreams: %w", &types.ThrottlingException{}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isThrottlingError(tt.err); got != tt.want {
+				t.Fatalf("isThrottlingError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRetrySucceedsWithoutRetrying(t *testing.T) {
+	calls := 0
+	err := withRetry(context.Background(), defaultRetryConfig, func() error {
+		calls++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("withRetry: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("got %d calls, want 1", calls)
+	}
+}
+
+func TestWithRetryReturnsNonThrottlingErrorImmediately(t *testing.T) {
+	wantErr := errors.New("not a throttling error")
+	calls := 0
+	err := withRetry(context.Background(), defaultRetryConfig, func() error {
+		calls++
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("got err %v, want %v", err, wantErr)
+	}
+	if calls != 1 {
+		t.Fatalf("got %d calls, want 1 (non-throttling errors should not be retried)", calls)
+	}
+}
+
+func TestWithRetryRetriesThrottlingErrorsUntilSuccess(t *testing.T) {
+	cfg := retryConfig{maxAttempts: 5, initialInterval: time.Millisecond, backoffFactor: 2, maxInterval: 10 * time.Millisecond}
+
+	calls := 0
+	err := withRetry(context.Background(), cfg, func() error {
+		calls++
+		if calls < 3 {
+			return &types.ThrottlingException{}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("withRetry: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("got %d calls, want 3", calls)
+	}
+}
+
+func TestWithRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	cfg := retryConfig{maxAttempts: 3, initialInterval: time.Millisecond, backoffFactor: 2, maxInterval: 10 * time.Millisecond}
+
+	calls := 0
+	err := withRetry(context.Background(), cfg, func() error {
+		calls++
+		return &types.ThrottlingException{}
+	})
+	if !isThrottlingError(err) {
+		t.Fatalf("got err %v, want a throttling error", err)
+	}
+	if calls != cfg.maxAttempts {
+		t.Fatalf("got %d calls, want %d (maxAttempts)", calls, cfg.maxAttempts)
+	}
+}
+
+func TestWithRetryRespectsContextCancellation(t *testing.T) {
+	cfg := retryConfig{maxAttempts: 5, initialInterval: 100 * time.Millisecond, backoffFactor: 2, maxInterval: time.Second}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := withRetry(ctx, cfg, func() error {
+		calls++
+		if calls == 1 {
+			cancel()
+		}
+		return &types.ThrottlingException{}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got err %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// cloudWatchLogsTPSLimit is the default CloudWatch Logs DescribeLogStreams throttling
+// ceiling (requests per second) per account, shared across all workers in a pool.
+const cloudWatchLogsTPSLimit = 5
+
+// rateLimiter is a simple token-bucket limiter shared across all workers in a pool so a
+// batch of concurrent CloudWatch Logs calls stays under the account's TPS ceiling.
+type rateLimiter struct {
+	tokens chan struct{}
+}
+
+// newRateLimiter creates a token-bucket limiter that refills ratePerSecond tokens every
+// second until ctx is done.
+func newRateLimiter(ctx context.Context, ratePerSecond int) *rateLimiter {
+	rl := &rateLimiter{
+		tokens: make(chan struct{}, ratePerSecond),
+	}
+
+	for i := 0; i < ratePerSecond; i++ {
+		rl.tokens <- struct{}{}
+	}
+
+	go rl.refill(ctx, ratePerSecond)
+
+	return rl
+}
+
+func (rl *rateLimiter) refill(ctx context.Context, ratePerSecond int) {
+	ticker := time.NewTicker(time.Second / time.Duration(ratePerSecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			select {
+			case rl.tokens <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// wait blocks until a token is available or ctx is done.
+func (rl *rateLimiter) wait(ctx context.Context) error {
+	select {
+	case <-rl.tokens:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// workerPool runs a bounded number of jobs concurrently, sharing a rate limiter across
+// all workers so downstream AWS API calls stay under a shared TPS ceiling.
+type workerPool struct {
+	concurrency int
+	limiter     *rateLimiter
+}
+
+// newWorkerPool creates a workerPool with the given concurrency, backed by a token-bucket
+// rate limiter allowing ratePerSecond operations per second across all workers.
+func newWorkerPool(ctx context.Context, concurrency, ratePerSecond int) *workerPool {
+	return &workerPool{
+		concurrency: concurrency,
+		limiter:     newRateLimiter(ctx, ratePerSecond),
+	}
+}
+
+// run calls fn(ctx, i) for every i in [0, n), bounded to p.concurrency concurrent
+// goroutines, each waiting on the shared rate limiter before calling fn.
+func (p *workerPool) run(ctx context.Context, n int, fn func(ctx context.Context, index int)) {
+	sem := make(chan struct{}, p.concurrency)
+	wg := &sync.WaitGroup{}
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(index int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := p.limiter.wait(ctx); err != nil {
+				return
+			}
+
+			fn(ctx, index)
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// runBounded calls fn(i) for every i in [0, n), bounded to at most concurrency goroutines
+// running at once, with no additional rate limiting. It's used for work that has its own
+// per-item throttling concerns (such as enumerating a list of profile/region targets)
+// rather than a single shared downstream API ceiling.
+func runBounded(n, concurrency int, fn func(index int)) {
+	sem := make(chan struct{}, concurrency)
+	wg := &sync.WaitGroup{}
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(index int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(index)
+		}(i)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkerPoolRunCallsEveryIndexOnce(t *testing.T) {
+	const n = 50
+	pool := newWorkerPool(context.Background(), 4, 1000)
+
+	var mu sync.Mutex
+	seen := make(map[int]int)
+
+	pool.run(context.Background(), n, func(ctx context.Context, index int) {
+		mu.Lock()
+		seen[index]++
+		mu.Unlock()
+	})
+
+	if len(seen) != n {
+		t.Fatalf("got %d distinct indexes, want %d", len(seen), n)
+	}
+	for i := 0; i < n; i++ {
+		if seen[i] != 1 {
+			t.Fatalf("index %d was called %d times, want 1", i, seen[i])
+		}
+	}
+}
+
+func TestWorkerPoolRunBoundsConcurrency(t *testing.T) {
+	const concurrency = 3
+	pool := newWorkerPool(context.Background(), concurrency, 1000)
+
+	var (
+		inFlight int32
+		maxSeen  int32
+	)
+
+	pool.run(context.Background(), 30, func(ctx context.Context, index int) {
+		cur := atomic.AddInt32(&inFlight, 1)
+		for {
+			old := atomic.LoadInt32(&maxSeen)
+			if cur <= old || atomic.CompareAndSwapInt32(&maxSeen, old, cur) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+	})
+
+	if maxSeen > concurrency {
+		t.Fatalf("observed %d concurrent workers, want at most %d", maxSeen, concurrency)
+	}
+}
+
+func TestWorkerPoolRunStopsOnContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// ratePerSecond of 1 means the bucket starts with a single token and won't refill
+	// within this test's lifetime; draining that token first means every subsequent
+	// wait() can only be satisfied by a new token (never, here) or ctx.Done(), so once
+	// cancel() runs below, run() has no live path to calling fn at all.
+	pool := newWorkerPool(ctx, 2, 1)
+	if err := pool.limiter.wait(ctx); err != nil {
+		t.Fatalf("draining the initial token: %v", err)
+	}
+	cancel()
+
+	var calls int32
+	pool.run(ctx, 10, func(ctx context.Context, index int) {
+		atomic.AddInt32(&calls, 1)
+	})
+
+	if calls != 0 {
+		t.Fatalf("got %d calls after context cancellation, want 0", calls)
+	}
+}
+
+func TestRunBoundedCallsEveryIndexOnce(t *testing.T) {
+	const n = 50
+	var mu sync.Mutex
+	seen := make(map[int]int)
+
+	runBounded(n, 4, func(index int) {
+		mu.Lock()
+		seen[index]++
+		mu.Unlock()
+	})
+
+	if len(seen) != n {
+		t.Fatalf("got %d distinct indexes, want %d", len(seen), n)
+	}
+	for i := 0; i < n; i++ {
+		if seen[i] != 1 {
+			t.Fatalf("index %d was called %d times, want 1", i, seen[i])
+		}
+	}
+}
+
+func TestRateLimiterLimitsThroughput(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rl := newRateLimiter(ctx, 5)
+
+	for i := 0; i < 5; i++ {
+		if err := rl.wait(ctx); err != nil {
+			t.Fatalf("wait() on a pre-filled bucket: %v", err)
+		}
+	}
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer waitCancel()
+	if err := rl.wait(waitCtx); err == nil {
+		t.Fatal("expected wait() to block once the bucket is drained, but it returned immediately")
+	}
+}